@@ -52,6 +52,7 @@ import (
 var (
 	hostOverride   string
 	seedWorkspaces bool
+	skipPreflight  bool
 )
 
 func main() {
@@ -72,6 +73,8 @@ func run(ctx context.Context) error {
 	pflag.BoolVar(&seedWorkspaces, "seed-workspaces", false,
 		"Create the kube-bind workspace hierarchy under root before bootstrapping (standalone/admin use). "+
 			"When false (default, ManagedProvider) bootstrap into the existing provider workspace the kubeconfig points at.")
+	pflag.BoolVar(&skipPreflight, "skip-preflight", false,
+		"Skip the permission preflight that runs against the target workspace before anything is bootstrapped.")
 	pflag.Parse()
 
 	logger := klog.FromContext(ctx)
@@ -97,7 +100,7 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	if err := bootstrapProvider(ctx, config, seedWorkspaces, options.KCPKubeConfig); err != nil {
+	if err := bootstrapProvider(ctx, config, seedWorkspaces, skipPreflight, options.KCPKubeConfig); err != nil {
 		return err
 	}
 
@@ -159,11 +162,9 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 	// scope the client to the workspace
-	wsConfig := *config.ClientConfig
-	wsConfig.Host = config.ClientConfig.Host + deploy.KubeBindRootClusterName.RequestPath()
-	wsKubeClient, err := kubernetes.NewForConfig(&wsConfig)
+	wsKubeClient, err := workspaceClient(config.ClientConfig, deploy.KubeBindRootClusterName)
 	if err != nil {
-		return fmt.Errorf("failed to create workspace-scoped kubernetes client: %w", err)
+		return err
 	}
 	_ = kubeClient // keep for future use
 	if err := createBackendKubeconfigSecret(ctx, wsKubeClient, config.ClientConfig, hostOverride); err != nil {
@@ -182,10 +183,18 @@ func run(ctx context.Context) error {
 // When false (ManagedProvider, the default) the operator has already provisioned the provider
 // workspace and handed us a kubeconfig scoped to it, so we bootstrap the kube-bind APIs INTO
 // that workspace without creating any workspaces (the scoped service account can't).
-func bootstrapProvider(ctx context.Context, config *bootstrap.Config, seedWorkspaces bool, kcpKubeconfig string) error {
+//
+// Unless skipPreflight is set, the permissions each flow needs are checked up front (see
+// preflight) so a misconfigured kubeconfig fails before anything has been written.
+func bootstrapProvider(ctx context.Context, config *bootstrap.Config, seedWorkspaces, skipPreflight bool, kcpKubeconfig string) error {
 	logger := klog.FromContext(ctx)
 
 	if seedWorkspaces {
+		if !skipPreflight {
+			if err := preflight(ctx, config.ClientConfig, logicalcluster.NewPath("root"), seedPreflightChecks); err != nil {
+				return fmt.Errorf("preflight failed: %w", err)
+			}
+		}
 		deploy.KubeBindRootClusterName = logicalcluster.NewPath("root:providers:kube-bind")
 		server, err := bootstrap.NewServer(ctx, config)
 		if err != nil {
@@ -206,6 +215,15 @@ func bootstrapProvider(ctx context.Context, config *bootstrap.Config, seedWorksp
 	if err != nil {
 		return err
 	}
+	if !skipPreflight {
+		checks, err := providerPreflightChecks(ctx, config.ClientConfig, current)
+		if err != nil {
+			return fmt.Errorf("preflight failed: %w", err)
+		}
+		if err := preflight(ctx, config.ClientConfig, current, checks); err != nil {
+			return fmt.Errorf("preflight failed: %w", err)
+		}
+	}
 	logger.Info("Bootstrapping into existing provider workspace", "cluster", current.String())
 	deploy.KubeBindRootClusterName = current
 	bootstrapcore.KubeBindRootClusterName = current
@@ -248,17 +266,36 @@ func currentWorkspace(kubeconfigPath string) (logicalcluster.Path, error) {
 	return logicalcluster.NewPath(name), nil
 }
 
+// workspaceClient returns a kubernetes client scoped to the given workspace. restConfig must
+// point at the kcp base URL (bootstrap.NewConfig strips the workspace path from the host).
+func workspaceClient(restConfig *rest.Config, ws logicalcluster.Path) (kubernetes.Interface, error) {
+	wsConfig := *restConfig
+	wsConfig.Host = restConfig.Host + ws.RequestPath()
+	client, err := kubernetes.NewForConfig(&wsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace-scoped kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
 // ensureDefaultNamespace creates the "default" namespace in the given workspace if it does not
 // already exist. The core kube-bind bootstrap expects it (it places a ServiceAccount and token
-// Secret there), but an out-of-band provisioned provider workspace does not have one.
+// Secret there), but an out-of-band provisioned provider workspace does not have one. It is
+// looked up first so that a kubeconfig without namespace create permission works as long as
+// the namespace is already there.
 func ensureDefaultNamespace(ctx context.Context, restConfig *rest.Config, ws logicalcluster.Path) error {
 	logger := klog.FromContext(ctx)
 
-	wsConfig := *restConfig
-	wsConfig.Host = restConfig.Host + ws.RequestPath()
-	client, err := kubernetes.NewForConfig(&wsConfig)
+	client, err := workspaceClient(restConfig, ws)
 	if err != nil {
-		return fmt.Errorf("failed to create workspace-scoped kubernetes client: %w", err)
+		return err
+	}
+
+	if _, err := client.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{}); err == nil {
+		logger.V(2).Info("default namespace already exists", "cluster", ws.String())
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
 	}
 
 	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
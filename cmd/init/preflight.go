@@ -0,0 +1,460 @@
+/*
+Copyright 2026 The Platform Mesh Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
+
+	backendconfig "github.com/platform-mesh/kube-bind-provider/config/backend"
+	provider "github.com/platform-mesh/kube-bind-provider/config/provider"
+)
+
+// preflightCheck is one permission requirement of the bootstrap. It is met when every
+// permission of at least one of its alternatives is allowed. Most checks have a single
+// alternative holding a single permission; RBAC writes have more (see rbacEscalationChecks).
+type preflightCheck struct {
+	alternatives []checkAlternative
+}
+
+// checkAlternative is a set of permissions that together satisfy a preflightCheck.
+type checkAlternative struct {
+	// description names the alternative in errors. When empty, the permissions themselves are
+	// listed instead.
+	description string
+	attrs       []authorizationv1.ResourceAttributes
+}
+
+// require returns a single-permission check for each of attrs.
+func require(attrs ...authorizationv1.ResourceAttributes) []preflightCheck {
+	checks := make([]preflightCheck, 0, len(attrs))
+	for _, a := range attrs {
+		checks = append(checks, preflightCheck{alternatives: []checkAlternative{{attrs: []authorizationv1.ResourceAttributes{a}}}})
+	}
+	return checks
+}
+
+// wildcardAccess is full access to every resource, the only permission known to cover a role
+// whose rules are not part of the embedded manifests.
+var wildcardAccess = authorizationv1.ResourceAttributes{Group: "*", Resource: "*", Verb: "*"}
+
+// seedPreflightChecks are the permissions the seed flow needs in root to create the kube-bind
+// workspace hierarchy. Everything below that is bootstrapped into workspaces we just created.
+var seedPreflightChecks = require(
+	authorizationv1.ResourceAttributes{Group: "tenancy.kcp.io", Resource: "workspaces", Verb: "create"},
+	authorizationv1.ResourceAttributes{Group: "tenancy.kcp.io", Resource: "workspaces", Verb: "get"},
+)
+
+// upstreamWriteChecks are the permissions for what the upstream core + kube-bind bootstrap
+// writes into the provider workspace (the ServiceAccount and token Secret in default, the
+// kube-bind APIResourceSchemas and APIExport), plus the backend kubeconfig Secret and the
+// APIExport label update done by run. This repo's own manifests are covered by
+// manifestWriteChecks, which derives them from the embedded files.
+var upstreamWriteChecks = slices.Concat(
+	writeChecks("", "serviceaccounts", "default"),
+	writeChecks("", "secrets", "default"),
+	writeChecks("apis.kcp.io", "apiresourceschemas", ""),
+	writeChecks("apis.kcp.io", "apiexports", ""),
+)
+
+// providerPreflightChecks returns the checks for the ManagedProvider flow. ensureDefaultNamespace
+// only creates the default namespace when it is missing, so namespace create is required only
+// in that case (or when that cannot be determined) — an operator-provisioned workspace that
+// already has it must not be rejected.
+func providerPreflightChecks(ctx context.Context, restConfig *rest.Config, ws logicalcluster.Path) ([]preflightCheck, error) {
+	client, err := workspaceClient(restConfig, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := require(authorizationv1.ResourceAttributes{Resource: "namespaces", Name: "default", Verb: "get"})
+	_, err = client.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err), apierrors.IsForbidden(err):
+		// When the get is forbidden we cannot tell whether the namespace exists, so require
+		// create as well; otherwise granting get alone could fail the next run on create.
+		checks = append(checks, require(authorizationv1.ResourceAttributes{Resource: "namespaces", Verb: "create"})...)
+	case err != nil:
+		return nil, workspaceRequestError(ws, "look up default namespace", err)
+	}
+
+	objs, err := manifestObjects(provider.FS, backendconfig.FS)
+	if err != nil {
+		return nil, err
+	}
+	manifestChecks, err := manifestWriteChecks(ctx, client.Discovery(), ws, objs)
+	if err != nil {
+		return nil, err
+	}
+	escalationChecks, err := rbacEscalationChecks(objs)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(checks, upstreamWriteChecks, manifestChecks, escalationChecks), nil
+}
+
+// manifestObjects decodes every object in the given embedded manifest filesystems — the same
+// files run applies with confighelpers.Bootstrap.
+func manifestObjects(fss ...embed.FS) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	for _, fsys := range fss {
+		entries, err := fsys.ReadDir(".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded manifests: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			raw, err := fsys.ReadFile(entry.Name())
+			if err != nil {
+				return nil, fmt.Errorf("failed to read embedded manifest %s: %w", entry.Name(), err)
+			}
+			reader := kubeyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(raw)))
+			for {
+				doc, err := reader.Read()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return nil, fmt.Errorf("failed to read embedded manifest %s: %w", entry.Name(), err)
+				}
+				obj := map[string]any{}
+				if err := kubeyaml.Unmarshal(doc, &obj); err != nil {
+					return nil, fmt.Errorf("failed to decode embedded manifest %s: %w", entry.Name(), err)
+				}
+				if len(obj) == 0 {
+					continue
+				}
+				objs = append(objs, &unstructured.Unstructured{Object: obj})
+			}
+		}
+	}
+	return objs, nil
+}
+
+// manifestWriteChecks returns the write checks for every resource the given manifest objects
+// are applied as. Kinds are mapped to resources through a discovery RESTMapper, as
+// confighelpers.Bootstrap does, so the checks cannot drift from what is actually applied.
+func manifestWriteChecks(ctx context.Context, discoveryClient discovery.DiscoveryInterface, ws logicalcluster.Path, objs []*unstructured.Unstructured) ([]preflightCheck, error) {
+	logger := klog.FromContext(ctx)
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	var checks []preflightCheck
+	seen := sets.New[string]()
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		var resource schema.GroupVersionResource
+		namespace := obj.GetNamespace()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		switch {
+		case err == nil:
+			resource = mapping.Resource
+			if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+				namespace = ""
+			}
+		case meta.IsNoMatchError(err):
+			// The API may not be served in the workspace yet. Use the resource name the
+			// RESTMapper would derive for the kind rather than skipping the check.
+			resource, _ = meta.UnsafeGuessKindToResource(gvk)
+			logger.Info("kind not served in workspace yet, guessing its resource for preflight", "cluster", ws.String(), "kind", gvk.String(), "resource", resource.String())
+		default:
+			return nil, workspaceRequestError(ws, fmt.Sprintf("map %s to a resource", gvk.GroupKind()), err)
+		}
+
+		key := resource.GroupResource().String() + "/" + namespace
+		if seen.Has(key) {
+			continue
+		}
+		seen.Insert(key)
+		checks = append(checks, writeChecks(resource.Group, resource.Resource, namespace)...)
+	}
+	return checks, nil
+}
+
+// rbacEscalationChecks returns the checks RBAC's privilege-escalation prevention adds on top
+// of plain create and update for the roles and bindings in the manifests. Writing a role
+// requires escalate on it or holding every permission it grants; writing a binding requires
+// bind on the referenced role or holding every permission that role grants. A plain create
+// review tests neither.
+func rbacEscalationChecks(objs []*unstructured.Unstructured) ([]preflightCheck, error) {
+	roles := map[string]*unstructured.Unstructured{}
+	for _, obj := range objs {
+		if obj.GroupVersionKind().Group == rbacv1.GroupName && (obj.GetKind() == "ClusterRole" || obj.GetKind() == "Role") {
+			roles[roleKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())] = obj
+		}
+	}
+
+	var checks []preflightCheck
+	for _, obj := range objs {
+		if obj.GroupVersionKind().Group != rbacv1.GroupName {
+			continue
+		}
+		switch obj.GetKind() {
+		case "ClusterRole", "Role":
+			held, err := heldRoleAlternative(obj, obj.GetNamespace())
+			if err != nil {
+				return nil, err
+			}
+			checks = append(checks, preflightCheck{alternatives: []checkAlternative{
+				{attrs: []authorizationv1.ResourceAttributes{roleAttributes(obj.GetKind(), obj.GetNamespace(), obj.GetName(), "escalate")}},
+				held,
+			}})
+		case "ClusterRoleBinding", "RoleBinding":
+			refKind, _, _ := unstructured.NestedString(obj.Object, "roleRef", "kind")
+			refName, _, _ := unstructured.NestedString(obj.Object, "roleRef", "name")
+			refNamespace := ""
+			if refKind == "Role" {
+				refNamespace = obj.GetNamespace()
+			}
+			// The rules of a role that is not part of the manifests are unknown here, so only
+			// full access is known to cover it.
+			wildcard := wildcardAccess
+			wildcard.Namespace = obj.GetNamespace()
+			held := checkAlternative{description: "full access", attrs: []authorizationv1.ResourceAttributes{wildcard}}
+			if role, ok := roles[roleKey(refKind, refNamespace, refName)]; ok {
+				var err error
+				if held, err = heldRoleAlternative(role, obj.GetNamespace()); err != nil {
+					return nil, err
+				}
+			}
+			checks = append(checks, preflightCheck{alternatives: []checkAlternative{
+				// bind is authorized in the binding's namespace, also for a RoleBinding to a ClusterRole.
+				{attrs: []authorizationv1.ResourceAttributes{roleAttributes(refKind, obj.GetNamespace(), refName, "bind")}},
+				held,
+			}})
+		}
+	}
+	return checks, nil
+}
+
+// heldRoleAlternative returns the alternative of holding every permission the role grants,
+// within namespace (empty for cluster-wide).
+func heldRoleAlternative(role *unstructured.Unstructured, namespace string) (checkAlternative, error) {
+	var parsed struct {
+		Rules []rbacv1.PolicyRule `json:"rules"`
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(role.Object, &parsed); err != nil {
+		return checkAlternative{}, fmt.Errorf("failed to decode %s %s: %w", role.GetKind(), role.GetName(), err)
+	}
+
+	alt := checkAlternative{description: fmt.Sprintf("every permission granted by %s %s", role.GetKind(), role.GetName())}
+	for _, rule := range parsed.Rules {
+		if len(rule.NonResourceURLs) > 0 {
+			// Non-resource permissions can't be expressed as resource attributes.
+			wildcard := wildcardAccess
+			wildcard.Namespace = namespace
+			return checkAlternative{description: "full access", attrs: []authorizationv1.ResourceAttributes{wildcard}}, nil
+		}
+		names := rule.ResourceNames
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, group := range rule.APIGroups {
+			for _, r := range rule.Resources {
+				resource, subresource, _ := strings.Cut(r, "/")
+				for _, verb := range rule.Verbs {
+					for _, name := range names {
+						alt.attrs = append(alt.attrs, authorizationv1.ResourceAttributes{
+							Namespace:   namespace,
+							Verb:        verb,
+							Group:       group,
+							Resource:    resource,
+							Subresource: subresource,
+							Name:        name,
+						})
+					}
+				}
+			}
+		}
+	}
+	return alt, nil
+}
+
+// roleAttributes returns the attributes for verb on the named ClusterRole or Role.
+func roleAttributes(kind, namespace, name, verb string) authorizationv1.ResourceAttributes {
+	resource := "clusterroles"
+	if kind == "Role" {
+		resource = "roles"
+	}
+	return authorizationv1.ResourceAttributes{Group: rbacv1.GroupName, Resource: resource, Namespace: namespace, Name: name, Verb: verb}
+}
+
+func roleKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// writeChecks returns the create, get and update checks for a resource. The bootstrap creates
+// each object and, when it already exists (the init container re-runs on every backend pod
+// restart), gets and updates it in place instead.
+func writeChecks(group, resource, namespace string) []preflightCheck {
+	var attrs []authorizationv1.ResourceAttributes
+	for _, verb := range []string{"create", "get", "update"} {
+		attrs = append(attrs, authorizationv1.ResourceAttributes{Group: group, Resource: resource, Namespace: namespace, Verb: verb})
+	}
+	return require(attrs...)
+}
+
+// preflight verifies that the kcp API is reachable and that the kubeconfig identity holds the
+// given permissions in the workspace, before the bootstrap writes anything. All denied checks
+// are reported together so a misconfigured kubeconfig fails once with the full picture, rather
+// than partway through the bootstrap on whichever write happened to come first.
+func preflight(ctx context.Context, restConfig *rest.Config, ws logicalcluster.Path, checks []preflightCheck) error {
+	logger := klog.FromContext(ctx)
+
+	client, err := workspaceClient(restConfig, ws)
+	if err != nil {
+		return err
+	}
+
+	// Checks overlap (e.g. the default namespace Secrets), so review each permission once.
+	reviewed := map[string]bool{}
+	allowed := func(attrs authorizationv1.ResourceAttributes) (bool, error) {
+		key := describeCheck(attrs)
+		if ok, found := reviewed[key]; found {
+			return ok, nil
+		}
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attrs},
+		}
+		result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return false, workspaceRequestError(ws, "review access", err)
+		}
+		if !result.Status.Allowed {
+			logger.V(2).Info("access denied", "cluster", ws.String(), "permission", key, "reason", result.Status.Reason)
+		}
+		reviewed[key] = result.Status.Allowed
+		return result.Status.Allowed, nil
+	}
+
+	var denied []string
+	seen := sets.New[string]()
+	for _, check := range checks {
+		var missing []string
+		met := false
+		for _, alt := range check.alternatives {
+			var altMissing []string
+			for _, attrs := range alt.attrs {
+				ok, err := allowed(attrs)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					altMissing = append(altMissing, describeCheck(attrs))
+				}
+			}
+			if len(altMissing) == 0 {
+				met = true
+				break
+			}
+			missing = append(missing, describeAlternative(alt, altMissing))
+		}
+		if met {
+			logger.V(2).Info("preflight check passed", "cluster", ws.String(), "check", describeAlternative(check.alternatives[0], nil))
+			continue
+		}
+		desc := strings.Join(missing, " or ")
+		if seen.Has(desc) {
+			continue
+		}
+		seen.Insert(desc)
+		logger.Info("preflight check denied", "cluster", ws.String(), "check", desc)
+		denied = append(denied, desc)
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("kubeconfig is missing permissions in workspace %s: %s", ws, strings.Join(denied, "; "))
+	}
+	return nil
+}
+
+// describeAlternative renders an alternative for logs and errors. With missing set, it names
+// the permissions of the alternative that were denied.
+func describeAlternative(alt checkAlternative, missing []string) string {
+	if alt.description == "" {
+		if missing == nil {
+			for _, attrs := range alt.attrs {
+				missing = append(missing, describeCheck(attrs))
+			}
+		}
+		return strings.Join(missing, ", ")
+	}
+	if missing == nil {
+		return alt.description
+	}
+	return fmt.Sprintf("%s (missing %s)", alt.description, strings.Join(missing, ", "))
+}
+
+// workspaceRequestError describes a failed preflight request against the workspace. A 403 on
+// the request itself means the identity has no access to the workspace at all, which is the
+// most common misconfiguration; only errors that never got an API response suggest that kcp is
+// unreachable.
+func workspaceRequestError(ws logicalcluster.Path, action string, err error) error {
+	var status apierrors.APIStatus
+	switch {
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("failed to %s: identity has no access to workspace %s: %w", action, ws, err)
+	case errors.As(err, &status):
+		return fmt.Errorf("failed to %s in workspace %s: %w", action, ws, err)
+	default:
+		return fmt.Errorf("failed to %s in workspace %s (is the kcp API reachable?): %w", action, ws, err)
+	}
+}
+
+// describeCheck renders a permission check as
+// "verb group/resource[/subresource][/name] [in namespace]".
+func describeCheck(attrs authorizationv1.ResourceAttributes) string {
+	resource := attrs.Resource
+	if attrs.Group != "" {
+		resource = attrs.Group + "/" + resource
+	}
+	if attrs.Subresource != "" {
+		resource += "/" + attrs.Subresource
+	}
+	if attrs.Name != "" {
+		resource += "/" + attrs.Name
+	}
+	s := attrs.Verb + " " + resource
+	if attrs.Namespace != "" {
+		s += " in namespace " + attrs.Namespace
+	}
+	return s
+}